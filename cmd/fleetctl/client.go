@@ -0,0 +1,104 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/kolide/fleet/server/service"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+const (
+	configFlagName  = "config"
+	contextFlagName = "context"
+
+	defaultContextName = "default"
+)
+
+// Context maps a fleetctl context name (what --context selects) to its
+// connection details, as stored in the config file.
+type Context map[string]contextConfig
+
+type contextConfig struct {
+	Address        string `json:"address"`
+	Email          string `json:"email"`
+	Token          string `json:"token"`
+	TLSCertificate string `json:"tls-certificate"`
+	RootCA         string `json:"root-ca"`
+	Insecure       bool   `json:"insecure"`
+}
+
+func defaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".fleet/config"
+	}
+	return filepath.Join(home, ".fleet", "config")
+}
+
+func configFlag() cli.StringFlag {
+	return cli.StringFlag{
+		Name:  configFlagName,
+		Value: defaultConfigPath(),
+		Usage: "Path to the fleetctl config file",
+	}
+}
+
+func contextFlag() cli.StringFlag {
+	return cli.StringFlag{
+		Name:  contextFlagName,
+		Value: defaultContextName,
+		Usage: "Name of the fleetctl context to use, or a dynamic context such as @consul/fleet-prod",
+	}
+}
+
+func loadConfig(path string) (Context, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Context{defaultContextName: contextConfig{}}, nil
+		}
+		return nil, errors.Wrap(err, "error reading config file")
+	}
+
+	var cfg Context
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, errors.Wrap(err, "error parsing config file")
+	}
+
+	return cfg, nil
+}
+
+// Client wraps the Fleet service client, so commands can always reach
+// fleet.GetQueries(), etc.
+type Client struct {
+	*service.Client
+}
+
+// clientFromCLI builds a Fleet client for the current invocation. --context
+// is resolved through resolveContext rather than indexed directly into the
+// config file, so dynamic backends like @consul/<name> and @http/<name>
+// (see context_resolver.go) take effect here, not just contexts defined in
+// the static config file.
+func clientFromCLI(c *cli.Context) (*Client, error) {
+	cfg, err := loadConfig(c.String(configFlagName))
+	if err != nil {
+		return nil, errors.Wrap(err, "error loading config")
+	}
+
+	rc, err := resolveContext(cfg, c.String(contextFlagName))
+	if err != nil {
+		return nil, errors.Wrap(err, "error resolving context")
+	}
+
+	svc, err := service.NewClient(rc.Address, rc.Insecure, rc.RootCA, rc.TLSCert)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating Fleet client")
+	}
+	svc.SetToken(rc.Token)
+
+	return &Client{svc}, nil
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultConsulAddr   = "http://127.0.0.1:8500"
+	defaultConsulPrefix = "fleetctl"
+)
+
+// resolvedContext holds what clientFromCLI needs to build a Fleet client.
+type resolvedContext struct {
+	Address  string `json:"address"`
+	Token    string `json:"token"`
+	TLSCert  string `json:"tls_certificate"`
+	RootCA   string `json:"root_ca"`
+	Insecure bool   `json:"insecure"`
+}
+
+// ContextResolver looks up the connection details for a named fleetctl
+// context.
+type ContextResolver interface {
+	Resolve(name string) (*resolvedContext, error)
+}
+
+// FileResolver resolves a context from the local fleetctl config file.
+type FileResolver struct {
+	Config Context
+}
+
+func (r FileResolver) Resolve(name string) (*resolvedContext, error) {
+	cfg, ok := r.Config[name]
+	if !ok {
+		return nil, errors.Errorf("context %q is not defined in the fleetctl config file", name)
+	}
+
+	return &resolvedContext{
+		Address:  cfg.Address,
+		Token:    cfg.Token,
+		TLSCert:  cfg.TLSCertificate,
+		RootCA:   cfg.RootCA,
+		Insecure: cfg.Insecure,
+	}, nil
+}
+
+// ConsulResolver resolves a context from a Consul KV prefix, one key per
+// field: <prefix>/<name>/address, <prefix>/<name>/token, etc.
+type ConsulResolver struct {
+	Addr   string
+	Prefix string
+	Client *http.Client
+}
+
+func (r ConsulResolver) Resolve(name string) (*resolvedContext, error) {
+	get := func(key string) (string, error) {
+		url := fmt.Sprintf("%s/v1/kv/%s/%s/%s?raw", strings.TrimRight(r.Addr, "/"), r.Prefix, name, key)
+		resp, err := r.Client.Get(url)
+		if err != nil {
+			return "", errors.Wrapf(err, "consul lookup of %s", key)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotFound {
+			return "", nil
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", errors.Errorf("consul lookup of %s: unexpected status %s", key, resp.Status)
+		}
+
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return "", errors.Wrapf(err, "reading consul response for %s", key)
+		}
+		return string(b), nil
+	}
+
+	address, err := get("address")
+	if err != nil {
+		return nil, err
+	}
+	if address == "" {
+		return nil, errors.Errorf("context %q not found under consul prefix %s", name, r.Prefix)
+	}
+
+	token, err := get("token")
+	if err != nil {
+		return nil, err
+	}
+	rootCA, err := get("root_ca")
+	if err != nil {
+		return nil, err
+	}
+
+	return &resolvedContext{
+		Address: address,
+		Token:   token,
+		RootCA:  rootCA,
+	}, nil
+}
+
+// HTTPResolver resolves a context by asking an HTTP endpoint for the
+// connection details.
+type HTTPResolver struct {
+	Endpoint string
+	Client   *http.Client
+}
+
+func (r HTTPResolver) Resolve(name string) (*resolvedContext, error) {
+	if r.Endpoint == "" {
+		return nil, errors.New("FLEETCTL_CONTEXT_HTTP_ENDPOINT must be set to resolve an @http/<name> context")
+	}
+
+	url := fmt.Sprintf("%s/%s", strings.TrimRight(r.Endpoint, "/"), name)
+
+	resp, err := r.Client.Get(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving context %q via %s", name, r.Endpoint)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("resolving context %q: unexpected status %s", name, resp.Status)
+	}
+
+	var rc resolvedContext
+	if err := json.NewDecoder(resp.Body).Decode(&rc); err != nil {
+		return nil, errors.Wrapf(err, "decoding context %q from %s", name, r.Endpoint)
+	}
+
+	return &rc, nil
+}
+
+// contextCache memoizes resolved contexts for the lifetime of a single
+// fleetctl invocation.
+type contextCache struct {
+	mu       sync.Mutex
+	resolved map[string]*resolvedContext
+}
+
+var globalContextCache = &contextCache{resolved: make(map[string]*resolvedContext)}
+
+func (cc *contextCache) resolve(resolver ContextResolver, name string) (*resolvedContext, error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if rc, ok := cc.resolved[name]; ok {
+		return rc, nil
+	}
+
+	rc, err := resolver.Resolve(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cc.resolved[name] = rc
+	return rc, nil
+}
+
+// resolverForContext parses a --context value and returns the resolver that
+// should handle it along with the bare context name. "@backend/name" (e.g.
+// "@consul/fleet-prod") selects a dynamic backend; anything else resolves
+// against the local config file.
+func resolverForContext(cfg Context, value string) (ContextResolver, string) {
+	if !strings.HasPrefix(value, "@") {
+		return FileResolver{Config: cfg}, value
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(value, "@"), "/", 2)
+	if len(parts) != 2 {
+		return FileResolver{Config: cfg}, value
+	}
+
+	backend, name := parts[0], parts[1]
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	switch backend {
+	case "consul":
+		return ConsulResolver{Addr: consulAddr(), Prefix: consulPrefix(), Client: httpClient}, name
+	case "http":
+		return HTTPResolver{Endpoint: httpContextEndpoint(), Client: httpClient}, name
+	default:
+		return FileResolver{Config: cfg}, value
+	}
+}
+
+// consulAddr honors CONSUL_HTTP_ADDR, the same variable the official Consul
+// CLI reads.
+func consulAddr() string {
+	if addr := os.Getenv("CONSUL_HTTP_ADDR"); addr != "" {
+		return addr
+	}
+	return defaultConsulAddr
+}
+
+// consulPrefix is overridable via FLEETCTL_CONSUL_PREFIX.
+func consulPrefix() string {
+	if prefix := os.Getenv("FLEETCTL_CONSUL_PREFIX"); prefix != "" {
+		return prefix
+	}
+	return defaultConsulPrefix
+}
+
+// httpContextEndpoint is read from FLEETCTL_CONTEXT_HTTP_ENDPOINT.
+func httpContextEndpoint() string {
+	return os.Getenv("FLEETCTL_CONTEXT_HTTP_ENDPOINT")
+}
+
+// resolveContext resolves the --context flag value to connection details.
+// clientFromCLI calls this instead of indexing directly into the config file.
+func resolveContext(cfg Context, value string) (*resolvedContext, error) {
+	resolver, name := resolverForContext(cfg, value)
+	return globalContextCache.resolve(resolver, name)
+}
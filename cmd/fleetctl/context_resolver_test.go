@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+func TestResolverForContext(t *testing.T) {
+	cfg := Context{"prod": contextConfig{Address: "https://fleet.example.com"}}
+
+	resolver, name := resolverForContext(cfg, "prod")
+	if _, ok := resolver.(FileResolver); !ok {
+		t.Errorf("expected FileResolver for plain context name, got %T", resolver)
+	}
+	if name != "prod" {
+		t.Errorf("expected name %q, got %q", "prod", name)
+	}
+
+	resolver, name = resolverForContext(cfg, "@consul/fleet-prod")
+	if _, ok := resolver.(ConsulResolver); !ok {
+		t.Errorf("expected ConsulResolver for @consul context, got %T", resolver)
+	}
+	if name != "fleet-prod" {
+		t.Errorf("expected name %q, got %q", "fleet-prod", name)
+	}
+
+	resolver, name = resolverForContext(cfg, "@http/fleet-prod")
+	if _, ok := resolver.(HTTPResolver); !ok {
+		t.Errorf("expected HTTPResolver for @http context, got %T", resolver)
+	}
+	if name != "fleet-prod" {
+		t.Errorf("expected name %q, got %q", "fleet-prod", name)
+	}
+
+	resolver, name = resolverForContext(cfg, "@unknown/fleet-prod")
+	if _, ok := resolver.(FileResolver); !ok {
+		t.Errorf("expected FileResolver fallback for unknown backend, got %T", resolver)
+	}
+	if name != "@unknown/fleet-prod" {
+		t.Errorf("expected name %q, got %q", "@unknown/fleet-prod", name)
+	}
+}
+
+func TestResolveContextUsesFileResolver(t *testing.T) {
+	cfg := Context{"prod": contextConfig{Address: "https://fleet.example.com", Token: "tok"}}
+
+	rc, err := resolveContext(cfg, "prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rc.Address != "https://fleet.example.com" || rc.Token != "tok" {
+		t.Errorf("expected resolved context from file config, got %+v", rc)
+	}
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kolide/fleet/server/kolide"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+// sectionTable renders a heading followed by a borderless rows table.
+func sectionTable(heading string, rows [][]string) {
+	fmt.Printf("%s:\n", heading)
+
+	if len(rows) == 0 {
+		fmt.Println("  <none>")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorder(false)
+	table.SetColumnSeparator("")
+	table.SetHeaderLine(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.AppendBulk(rows)
+	table.Render()
+	fmt.Println()
+}
+
+func describePack(fleet *Client, name string) error {
+	pack, err := fleet.GetPack(name)
+	if err != nil {
+		return errors.Wrap(err, "could not get pack")
+	}
+
+	fmt.Printf("Pack: %s\n\n", pack.Name)
+
+	rows := [][]string{}
+	for _, q := range pack.Queries {
+		rows = append(rows, []string{
+			q.QueryName,
+			fmt.Sprintf("%d", q.Interval),
+			fmt.Sprintf("%t", q.Removed),
+			fmt.Sprintf("%t", q.Snapshot),
+		})
+	}
+	sectionTable("Queries (name, interval, removed, snapshot)", rows)
+
+	return nil
+}
+
+// packIncludesQuery reports whether pack schedules the given query.
+func packIncludesQuery(pack *kolide.PackSpec, queryName string) bool {
+	for _, q := range pack.Queries {
+		if q.QueryName == queryName {
+			return true
+		}
+	}
+	return false
+}
+
+func describeQuery(fleet *Client, name string) error {
+	query, err := fleet.GetQuery(name)
+	if err != nil {
+		return errors.Wrap(err, "could not get query")
+	}
+
+	fmt.Printf("Query: %s\n\n", query.Name)
+
+	packs, err := fleet.GetPacks()
+	if err != nil {
+		return errors.Wrap(err, "could not list packs")
+	}
+
+	gatingLabels := make(map[string]bool)
+
+	packRows := [][]string{}
+	for _, pack := range packs {
+		if !packIncludesQuery(pack, query.Name) {
+			continue
+		}
+
+		for _, q := range pack.Queries {
+			if q.QueryName == query.Name {
+				packRows = append(packRows, []string{pack.Name, fmt.Sprintf("%d", q.Interval)})
+			}
+		}
+
+		for _, label := range pack.Targets.Labels {
+			gatingLabels[label] = true
+		}
+	}
+	sectionTable("Referenced by packs (name, interval)", packRows)
+
+	labels, err := fleet.GetLabels()
+	if err != nil {
+		return errors.Wrap(err, "could not list labels")
+	}
+
+	labelRows := [][]string{}
+	for _, label := range labels {
+		if gatingLabels[label.Name] {
+			labelRows = append(labelRows, []string{label.Name, label.Query})
+		}
+	}
+	sectionTable("Labels that gate this query (name, query)", labelRows)
+
+	return nil
+}
+
+// findHostByUUID looks up a host by its exact UUID, the fallback findHost
+// points users at when a hostname is ambiguous.
+func findHostByUUID(fleet *Client, uuid string) (*kolide.HostResponse, error) {
+	all, err := fleet.GetHosts(kolide.HostListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list hosts")
+	}
+
+	for _, host := range all {
+		if host.Host.UUID == uuid {
+			return &host, nil
+		}
+	}
+	return nil, errors.Errorf("no host with uuid %q found", uuid)
+}
+
+// findHost returns the single host named or identified by name, trying an
+// exact hostname match first and a UUID match if that fails.
+func findHost(fleet *Client, name string) (*kolide.HostResponse, error) {
+	candidates, err := fleet.GetHosts(kolide.HostListOptions{HostnameContains: name})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list hosts")
+	}
+
+	matches := []kolide.HostResponse{}
+	for _, host := range candidates {
+		if host.Host.HostName == name {
+			matches = append(matches, host)
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return &matches[0], nil
+	case 0:
+		if host, err := findHostByUUID(fleet, name); err == nil {
+			return host, nil
+		}
+
+		if len(candidates) == 0 {
+			return nil, errors.Errorf("no host named %q found", name)
+		}
+
+		similar := make([]string, 0, len(candidates))
+		for _, host := range candidates {
+			similar = append(similar, host.Host.HostName)
+		}
+		return nil, errors.Errorf("no host named %q found; similar hostnames: %s", name, strings.Join(similar, ", "))
+	default:
+		ambiguous := make([]string, 0, len(matches))
+		for _, host := range matches {
+			ambiguous = append(ambiguous, host.Host.UUID)
+		}
+		return nil, errors.Errorf("%q matches multiple hosts; describe by uuid instead: %s", name, strings.Join(ambiguous, ", "))
+	}
+}
+
+// packTargetsHost reports whether pack targets host, directly or via a
+// label the host belongs to.
+func packTargetsHost(pack *kolide.PackSpec, host *kolide.HostResponse, hostLabels map[string]bool) bool {
+	for _, hostName := range pack.Targets.Hosts {
+		if hostName == host.Host.HostName {
+			return true
+		}
+	}
+
+	for _, label := range pack.Targets.Labels {
+		if hostLabels[label] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func describeHost(fleet *Client, name string) error {
+	host, err := findHost(fleet, name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Host: %s\n\n", host.DisplayText)
+
+	sectionTable("Details", [][]string{
+		{"uuid", host.Host.UUID},
+		{"platform", host.Host.Platform},
+		{"status", host.Status},
+		{"last check-in", host.Host.SeenTime.String()},
+	})
+
+	labels, err := fleet.GetLabels()
+	if err != nil {
+		return errors.Wrap(err, "could not list labels")
+	}
+
+	hostLabels := make(map[string]bool)
+	labelRows := [][]string{}
+	for _, label := range labels {
+		members, err := fleet.GetHosts(kolide.HostListOptions{Labels: []string{label.Name}})
+		if err != nil {
+			return errors.Wrapf(err, "could not check membership of label %q", label.Name)
+		}
+
+		for _, member := range members {
+			if member.Host.ID == host.Host.ID {
+				hostLabels[label.Name] = true
+				labelRows = append(labelRows, []string{label.Name})
+				break
+			}
+		}
+	}
+	sectionTable("Label memberships", labelRows)
+
+	packs, err := fleet.GetPacks()
+	if err != nil {
+		return errors.Wrap(err, "could not list packs")
+	}
+
+	packRows := [][]string{}
+	for _, pack := range packs {
+		if packTargetsHost(pack, host, hostLabels) {
+			packRows = append(packRows, []string{pack.Name, pack.Platform})
+		}
+	}
+	sectionTable("Assigned packs (name, platform)", packRows)
+
+	return nil
+}
+
+func describeCommand() cli.Command {
+	return cli.Command{
+		Name:  "describe",
+		Usage: "Show detailed, human-readable information about a resource and how it relates to others",
+		Flags: []cli.Flag{
+			configFlag(),
+			contextFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			fleet, err := clientFromCLI(c)
+			if err != nil {
+				return err
+			}
+
+			kind := c.Args().Get(0)
+			name := c.Args().Get(1)
+			if kind == "" || name == "" {
+				return errors.New("usage: fleetctl describe <kind> <name>")
+			}
+
+			switch kind {
+			case "pack", "packs":
+				return describePack(fleet, name)
+			case "query", "queries":
+				return describeQuery(fleet, name)
+			case "host", "hosts":
+				return describeHost(fleet, name)
+			default:
+				return errors.Errorf("cannot describe resource of kind %q", kind)
+			}
+		},
+	}
+}
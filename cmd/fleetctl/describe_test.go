@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/kolide/fleet/server/kolide"
+)
+
+func TestPackIncludesQuery(t *testing.T) {
+	pack := &kolide.PackSpec{
+		Queries: []kolide.PackSpecQuery{
+			{QueryName: "osquery_info"},
+		},
+	}
+
+	if !packIncludesQuery(pack, "osquery_info") {
+		t.Error("expected pack to include osquery_info")
+	}
+	if packIncludesQuery(pack, "other_query") {
+		t.Error("expected pack not to include other_query")
+	}
+}
+
+func TestPackTargetsHost(t *testing.T) {
+	pack := &kolide.PackSpec{
+		Targets: kolide.PackSpecTargets{
+			Hosts:  []string{"direct-host"},
+			Labels: []string{"label1"},
+		},
+	}
+	host := &kolide.HostResponse{Host: kolide.Host{HostName: "direct-host"}}
+
+	if !packTargetsHost(pack, host, map[string]bool{}) {
+		t.Error("expected pack to target host directly")
+	}
+
+	other := &kolide.HostResponse{Host: kolide.Host{HostName: "other-host"}}
+	if packTargetsHost(pack, other, map[string]bool{}) {
+		t.Error("expected pack not to target unrelated host")
+	}
+	if !packTargetsHost(pack, other, map[string]bool{"label1": true}) {
+		t.Error("expected pack to target host via label")
+	}
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/kolide/fleet/server/kolide"
@@ -13,7 +15,11 @@ import (
 
 const (
 	yamlFlagName        = "yaml"
+	jsonFlagName        = "json"
 	withQueriesFlagName = "with-queries"
+
+	formatYAML = "yaml"
+	formatJSON = "json"
 )
 
 type specGeneric struct {
@@ -31,50 +37,61 @@ func defaultTable() *tablewriter.Table {
 func yamlFlag() cli.BoolFlag {
 	return cli.BoolFlag{
 		Name:  yamlFlagName,
-		Usage: "Output packs in yaml format",
+		Usage: "Output in yaml format",
 	}
 }
 
-func printQuery(query *kolide.QuerySpec, yamlSeparator bool) error {
-	spec := specGeneric{
-		Kind:    "query",
-		Version: kolide.ApiVersion,
-		Spec:    query,
-	}
-
-	b, err := yaml.Marshal(spec)
-	if err != nil {
-		return err
+func jsonFlag() cli.BoolFlag {
+	return cli.BoolFlag{
+		Name:  jsonFlagName,
+		Usage: "Output in JSON format",
 	}
+}
 
-	sep := ""
-	if yamlSeparator {
-		sep = "---\n"
+// outputFormat determines which spec format a command should print in,
+// preferring --json over --yaml when both are (mistakenly) set.
+func outputFormat(c *cli.Context) string {
+	if c.Bool(jsonFlagName) {
+		return formatJSON
 	}
-
-	fmt.Printf("%s%s", sep, string(b))
-	return nil
+	return formatYAML
 }
 
-func printPack(pack *kolide.PackSpec, yamlSeparator bool) error {
-	spec := specGeneric{
-		Kind:    "pack",
+// printSpec marshals the given kind/spec pair into a specGeneric document
+// and writes it to stdout in the requested format. When multi is true and
+// format is yaml, a "---" document separator is written first so that
+// repeated calls produce a valid multi-document YAML stream; JSON output is
+// always newline-delimited, so multi has no effect there.
+func printSpec(kind string, spec interface{}, format string, multi bool) error {
+	out := specGeneric{
+		Kind:    kind,
 		Version: kolide.ApiVersion,
-		Spec:    pack,
-	}
-
-	b, err := yaml.Marshal(spec)
-	if err != nil {
-		return err
+		Spec:    spec,
 	}
 
-	sep := ""
-	if yamlSeparator {
-		sep = "---\n"
+	switch format {
+	case formatJSON:
+		b, err := json.Marshal(out)
+		if err != nil {
+			return errors.Wrap(err, "error marshaling spec to json")
+		}
+
+		fmt.Println(string(b))
+		return nil
+	default:
+		b, err := yaml.Marshal(out)
+		if err != nil {
+			return errors.Wrap(err, "error marshaling spec to yaml")
+		}
+
+		sep := ""
+		if multi {
+			sep = "---\n"
+		}
+
+		fmt.Printf("%s%s", sep, string(b))
+		return nil
 	}
-
-	fmt.Printf("%s%s", sep, string(b))
-	return nil
 }
 
 func getQueriesCommand() cli.Command {
@@ -86,6 +103,9 @@ func getQueriesCommand() cli.Command {
 			configFlag(),
 			contextFlag(),
 			yamlFlag(),
+			jsonFlag(),
+			watchFlag(),
+			watchIntervalFlag(),
 		},
 		Action: func(c *cli.Context) error {
 			fleet, err := clientFromCLI(c)
@@ -93,53 +113,64 @@ func getQueriesCommand() cli.Command {
 				return err
 			}
 
+			format := outputFormat(c)
+
 			name := c.Args().First()
 
 			// if name wasn't provided, list all queries
 			if name == "" {
-				queries, err := fleet.GetQueries()
-				if err != nil {
-					return errors.Wrap(err, "could not list queries")
-				}
+				render := func() (int, error) {
+					queries, err := fleet.GetQueries()
+					if err != nil {
+						return 0, errors.Wrap(err, "could not list queries")
+					}
 
-				if c.Bool(yamlFlagName) {
-					for _, query := range queries {
-						if err := printQuery(query, true); err != nil {
-							return errors.Wrap(err, "unable to print query")
+					if c.Bool(yamlFlagName) || c.Bool(jsonFlagName) {
+						for _, query := range queries {
+							if err := printSpec("query", query, format, true); err != nil {
+								return 0, errors.Wrap(err, "unable to print query")
+							}
 						}
+
+						return 0, nil
 					}
 
-					return nil
-				}
+					if len(queries) == 0 {
+						fmt.Println("no queries found")
+						return 1, nil
+					}
 
-				if len(queries) == 0 {
-					fmt.Println("no queries found")
-					return nil
-				}
+					data := [][]string{}
 
-				data := [][]string{}
+					for _, query := range queries {
+						data = append(data, []string{
+							query.Name,
+							query.Description,
+							query.Query,
+						})
+					}
 
-				for _, query := range queries {
-					data = append(data, []string{
-						query.Name,
-						query.Description,
-						query.Query,
-					})
+					table := defaultTable()
+					table.SetHeader([]string{"name", "description", "query"})
+					table.AppendBulk(data)
+					table.Render()
+
+					return 2*len(data) + 4, nil
 				}
 
-				table := defaultTable()
-				table.SetHeader([]string{"name", "description", "query"})
-				table.AppendBulk(data)
-				table.Render()
+				if c.Bool(watchFlagName) {
+					return watchLoop(c.Duration(watchIntervalFlagName), render)
+				}
 
-				return nil
+				_, err := render()
+				return err
 			} else {
 				query, err := fleet.GetQuery(name)
 				if err != nil {
 					return err
 				}
 
-				if err := printQuery(query, false); err != nil {
+				if err := printSpec("query", query, format, false); err != nil {
 					return errors.Wrap(err, "unable to print query")
 				}
 
@@ -158,10 +189,13 @@ func getPacksCommand() cli.Command {
 			configFlag(),
 			contextFlag(),
 			yamlFlag(),
+			jsonFlag(),
 			cli.BoolFlag{
 				Name:  withQueriesFlagName,
 				Usage: "Output queries included in pack(s) too",
 			},
+			watchFlag(),
+			watchIntervalFlag(),
 		},
 		Action: func(c *cli.Context) error {
 			fleet, err := clientFromCLI(c)
@@ -169,6 +203,8 @@ func getPacksCommand() cli.Command {
 				return err
 			}
 
+			format := outputFormat(c)
+
 			name := c.Args().First()
 			shouldPrintQueries := c.Bool(withQueriesFlagName)
 			queriesToPrint := make(map[string]bool)
@@ -198,7 +234,7 @@ func getPacksCommand() cli.Command {
 						continue
 					}
 
-					if err := printQuery(query, true); err != nil {
+					if err := printSpec("query", query, format, true); err != nil {
 						return errors.Wrap(err, "unable to print query")
 					}
 				}
@@ -208,44 +244,53 @@ func getPacksCommand() cli.Command {
 
 			// if name wasn't provided, list all packs
 			if name == "" {
-				packs, err := fleet.GetPacks()
-				if err != nil {
-					return errors.Wrap(err, "could not list packs")
-				}
+				render := func() (int, error) {
+					packs, err := fleet.GetPacks()
+					if err != nil {
+						return 0, errors.Wrap(err, "could not list packs")
+					}
 
-				if c.Bool(yamlFlagName) {
-					for _, pack := range packs {
-						if err := printPack(pack, true); err != nil {
-							return errors.Wrap(err, "unable to print pack")
+					if c.Bool(yamlFlagName) || c.Bool(jsonFlagName) {
+						for _, pack := range packs {
+							if err := printSpec("pack", pack, format, true); err != nil {
+								return 0, errors.Wrap(err, "unable to print pack")
+							}
+
+							addQueries(pack)
 						}
 
-						addQueries(pack)
+						return 0, printQueries()
 					}
 
-					return printQueries()
-				}
+					if len(packs) == 0 {
+						fmt.Println("no packs found")
+						return 1, nil
+					}
 
-				if len(packs) == 0 {
-					fmt.Println("no packs found")
-					return nil
-				}
+					data := [][]string{}
 
-				data := [][]string{}
+					for _, pack := range packs {
+						data = append(data, []string{
+							pack.Name,
+							pack.Platform,
+							pack.Description,
+						})
+					}
 
-				for _, pack := range packs {
-					data = append(data, []string{
-						pack.Name,
-						pack.Platform,
-						pack.Description,
-					})
+					table := defaultTable()
+					table.SetHeader([]string{"name", "platform", "description"})
+					table.AppendBulk(data)
+					table.Render()
+
+					return 2*len(data) + 4, nil
 				}
 
-				table := defaultTable()
-				table.SetHeader([]string{"name", "platform", "description"})
-				table.AppendBulk(data)
-				table.Render()
+				if c.Bool(watchFlagName) {
+					return watchLoop(c.Duration(watchIntervalFlagName), render)
+				}
 
-				return nil
+				_, err := render()
+				return err
 			} else {
 				pack, err := fleet.GetPack(name)
 				if err != nil {
@@ -254,7 +299,7 @@ func getPacksCommand() cli.Command {
 
 				addQueries(pack)
 
-				if err := printPack(pack, shouldPrintQueries); err != nil {
+				if err := printSpec("pack", pack, format, shouldPrintQueries); err != nil {
 					return errors.Wrap(err, "unable to print pack")
 				}
 
@@ -273,6 +318,7 @@ func getLabelsCommand() cli.Command {
 			configFlag(),
 			contextFlag(),
 			yamlFlag(),
+			jsonFlag(),
 		},
 		Action: func(c *cli.Context) error {
 			fleet, err := clientFromCLI(c)
@@ -280,6 +326,8 @@ func getLabelsCommand() cli.Command {
 				return err
 			}
 
+			format := outputFormat(c)
+
 			name := c.Args().First()
 
 			// if name wasn't provided, list all labels
@@ -289,20 +337,11 @@ func getLabelsCommand() cli.Command {
 					return errors.Wrap(err, "could not list labels")
 				}
 
-				if c.Bool(yamlFlagName) {
+				if c.Bool(yamlFlagName) || c.Bool(jsonFlagName) {
 					for _, label := range labels {
-						spec := specGeneric{
-							Kind:    "label",
-							Version: kolide.ApiVersion,
-							Spec:    label,
-						}
-
-						b, err := yaml.Marshal(spec)
-						if err != nil {
-							return err
+						if err := printSpec("label", label, format, true); err != nil {
+							return errors.Wrap(err, "unable to print label")
 						}
-
-						fmt.Printf("---\n%s", string(b))
 					}
 					return nil
 				}
@@ -335,20 +374,7 @@ func getLabelsCommand() cli.Command {
 					return err
 				}
 
-				spec := specGeneric{
-					Kind:    "label",
-					Version: kolide.ApiVersion,
-					Spec:    label,
-				}
-
-				b, err := yaml.Marshal(spec)
-				if err != nil {
-					return err
-				}
-
-				fmt.Print(string(b))
-
-				return nil
+				return printSpec("label", label, format, false)
 			}
 		},
 	}
@@ -361,6 +387,7 @@ func getOptionsCommand() cli.Command {
 		Flags: []cli.Flag{
 			configFlag(),
 			contextFlag(),
+			jsonFlag(),
 		},
 		Action: func(c *cli.Context) error {
 			fleet, err := clientFromCLI(c)
@@ -373,19 +400,7 @@ func getOptionsCommand() cli.Command {
 				return err
 			}
 
-			spec := specGeneric{
-				Kind:    "options",
-				Version: kolide.ApiVersion,
-				Spec:    options,
-			}
-
-			b, err := yaml.Marshal(spec)
-			if err != nil {
-				return err
-			}
-
-			fmt.Print(string(b))
-			return nil
+			return printSpec("options", options, outputFormat(c), false)
 		},
 	}
 }
@@ -398,6 +413,7 @@ func getEnrollSecretCommand() cli.Command {
 		Flags: []cli.Flag{
 			configFlag(),
 			contextFlag(),
+			jsonFlag(),
 		},
 		Action: func(c *cli.Context) error {
 			fleet, err := clientFromCLI(c)
@@ -410,19 +426,7 @@ func getEnrollSecretCommand() cli.Command {
 				return err
 			}
 
-			spec := specGeneric{
-				Kind:    "enroll_secret",
-				Version: kolide.ApiVersion,
-				Spec:    secrets,
-			}
-
-			b, err := yaml.Marshal(spec)
-			if err != nil {
-				return err
-			}
-
-			fmt.Print(string(b))
-			return nil
+			return printSpec("enroll_secret", secrets, outputFormat(c), false)
 		},
 	}
 }
@@ -434,6 +438,7 @@ func getAppConfigCommand() cli.Command {
 		Flags: []cli.Flag{
 			configFlag(),
 			contextFlag(),
+			jsonFlag(),
 		},
 		Action: func(c *cli.Context) error {
 			fleet, err := clientFromCLI(c)
@@ -446,20 +451,39 @@ func getAppConfigCommand() cli.Command {
 				return err
 			}
 
-			spec := specGeneric{
-				Kind:    "config",
-				Version: kolide.ApiVersion,
-				Spec:    config,
-			}
+			return printSpec("config", config, outputFormat(c), false)
+		},
+	}
+}
 
-			b, err := yaml.Marshal(spec)
-			if err != nil {
-				return err
-			}
+const (
+	outputFlagName           = "o"
+	platformFlagName         = "platform"
+	statusFlagName           = "status"
+	labelFlagName            = "label"
+	hostnameContainsFlagName = "hostname-contains"
+
+	outputTable = "table"
+	outputWide  = "wide"
+)
 
-			fmt.Print(string(b))
-			return nil
-		},
+func outputFlag() cli.StringFlag {
+	return cli.StringFlag{
+		Name:  outputFlagName,
+		Usage: "Output format: table|wide|yaml|json",
+		Value: outputTable,
+	}
+}
+
+// hostListOptionsFromCLI builds the server-side filter that getHostsCommand
+// pushes down to the Fleet API, so that we never have to pull every host
+// just to filter client-side.
+func hostListOptionsFromCLI(c *cli.Context) kolide.HostListOptions {
+	return kolide.HostListOptions{
+		Platform:         c.String(platformFlagName),
+		Status:           c.String(statusFlagName),
+		Labels:           c.StringSlice(labelFlagName),
+		HostnameContains: c.String(hostnameContainsFlagName),
 	}
 }
 
@@ -471,6 +495,25 @@ func getHostsCommand() cli.Command {
 		Flags: []cli.Flag{
 			configFlag(),
 			contextFlag(),
+			outputFlag(),
+			cli.StringFlag{
+				Name:  platformFlagName,
+				Usage: "Only include hosts running this platform",
+			},
+			cli.StringFlag{
+				Name:  statusFlagName,
+				Usage: "Only include hosts with this status (online, offline, new, mia)",
+			},
+			cli.StringSliceFlag{
+				Name:  labelFlagName,
+				Usage: "Only include hosts in this label (may be repeated)",
+			},
+			cli.StringFlag{
+				Name:  hostnameContainsFlagName,
+				Usage: "Only include hosts whose hostname contains this substring",
+			},
+			watchFlag(),
+			watchIntervalFlag(),
 		},
 		Action: func(c *cli.Context) error {
 			fleet, err := clientFromCLI(c)
@@ -478,33 +521,121 @@ func getHostsCommand() cli.Command {
 				return err
 			}
 
-			hosts, err := fleet.GetHosts()
-			if err != nil {
-				return errors.Wrap(err, "could not list hosts")
-			}
+			output := c.String(outputFlagName)
+			opts := hostListOptionsFromCLI(c)
+
+			cache := newHostCache()
+			var since time.Time
+			first := true
+			tick := 0
+
+			render := func() (int, error) {
+				var (
+					changed     []kolide.HostResponse
+					err         error
+					fullRefresh bool
+				)
+
+				// Every fullRefreshEveryTicks ticks (and always on the first
+				// call) we do a full GetHosts rather than an incremental
+				// GetHostsSince. This is what picks up hosts that were
+				// deleted from Fleet (GetHostsSince has no way to signal a
+				// deletion) and statuses that change purely from elapsed
+				// time (e.g. online -> offline) without necessarily bumping
+				// a host's updated_at.
+				if first || tick%fullRefreshEveryTicks == 0 {
+					changed, err = fleet.GetHosts(opts)
+					fullRefresh = true
+					first = false
+				} else {
+					changed, err = fleet.GetHostsSince(opts, since)
+				}
+				tick++
+				if err != nil {
+					return 0, errors.Wrap(err, "could not list hosts")
+				}
 
-			if len(hosts) == 0 {
-				fmt.Println("no hosts found")
-				return nil
-			}
+				// Advance the cursor using the newest updated_at the server
+				// actually returned, not the client's wall clock, so that a
+				// host updated between the server's query and this process
+				// receiving the response isn't skipped by the next poll.
+				for _, host := range changed {
+					if host.Host.UpdatedAt.After(since) {
+						since = host.Host.UpdatedAt
+					}
+				}
 
-			data := [][]string{}
+				if fullRefresh {
+					cache.reset(changed)
+				} else {
+					cache.merge(changed)
+				}
+				hosts := cache.list()
+
+				switch output {
+				case formatYAML, formatJSON:
+					for _, host := range hosts {
+						if err := printSpec("host", host.HostSpec(), output, true); err != nil {
+							return 0, errors.Wrap(err, "unable to print host")
+						}
+					}
+
+					return 0, nil
+				}
+
+				if len(hosts) == 0 {
+					fmt.Println("no hosts found")
+					return 1, nil
+				}
 
-			for _, host := range hosts {
-				data = append(data, []string{
-					host.Host.UUID,
-					host.DisplayText,
-					host.Host.Platform,
-					host.Status,
-				})
+				data := [][]string{}
+
+				if output == outputWide {
+					for _, host := range hosts {
+						data = append(data, []string{
+							host.Host.UUID,
+							host.DisplayText,
+							host.Host.Platform,
+							host.Status,
+							host.Host.OSVersion,
+							host.Host.OsqueryVersion,
+							host.Host.SeenTime.String(),
+							host.Host.PrimaryIP,
+							host.Host.HardwareSerial,
+						})
+					}
+
+					table := defaultTable()
+					table.SetHeader([]string{"uuid", "hostname", "platform", "status", "os version", "osquery version", "last seen", "ip", "serial"})
+					table.AppendBulk(data)
+					table.Render()
+
+					return 2*len(data) + 4, nil
+				}
+
+				for _, host := range hosts {
+					data = append(data, []string{
+						host.Host.UUID,
+						host.DisplayText,
+						host.Host.Platform,
+						host.Status,
+					})
+				}
+
+				table := defaultTable()
+				table.SetHeader([]string{"uuid", "hostname", "platform", "status"})
+				table.AppendBulk(data)
+				table.Render()
+
+				return 2*len(data) + 4, nil
 			}
 
-			table := defaultTable()
-			table.SetHeader([]string{"uuid", "hostname", "platform", "status"})
-			table.AppendBulk(data)
-			table.Render()
+			if c.Bool(watchFlagName) {
+				return watchLoop(c.Duration(watchIntervalFlagName), render)
+			}
 
-			return nil
+			_, err = render()
+			return err
 		},
 	}
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	fn()
+
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("reading captured stdout: %v", err)
+	}
+	return string(out)
+}
+
+func TestPrintSpecJSON(t *testing.T) {
+	out := captureStdout(t, func() {
+		if err := printSpec("query", map[string]string{"name": "foo"}, formatJSON, true); err != nil {
+			t.Fatalf("printSpec: %v", err)
+		}
+	})
+
+	if !strings.Contains(out, `"kind":"query"`) {
+		t.Errorf("expected kind in json output, got %q", out)
+	}
+	if strings.Contains(out, "---") {
+		t.Errorf("expected no document separator in json output, got %q", out)
+	}
+}
+
+func TestPrintSpecYAMLMulti(t *testing.T) {
+	out := captureStdout(t, func() {
+		if err := printSpec("query", map[string]string{"name": "foo"}, formatYAML, true); err != nil {
+			t.Fatalf("printSpec: %v", err)
+		}
+	})
+
+	if !strings.HasPrefix(out, "---\n") {
+		t.Errorf("expected yaml output to lead with a document separator, got %q", out)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli"
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "fleetctl"
+	app.Usage = "Manage your osquery fleet"
+	app.Commands = []cli.Command{
+		{
+			Name:  "get",
+			Usage: "Get/list resources",
+			Subcommands: []cli.Command{
+				getQueriesCommand(),
+				getPacksCommand(),
+				getLabelsCommand(),
+				getOptionsCommand(),
+				getAppConfigCommand(),
+				getEnrollSecretCommand(),
+				getHostsCommand(),
+			},
+		},
+		describeCommand(),
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
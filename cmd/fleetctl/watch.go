@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kolide/fleet/server/kolide"
+	"github.com/urfave/cli"
+)
+
+const (
+	watchFlagName         = "watch"
+	watchIntervalFlagName = "watch-interval"
+
+	defaultWatchInterval = 2 * time.Second
+
+	// fullRefreshEveryTicks caps how many incremental ticks pass between
+	// full GetHosts calls, so deletions and stale statuses get reconciled.
+	fullRefreshEveryTicks = 15
+)
+
+func watchFlag() cli.BoolFlag {
+	return cli.BoolFlag{
+		Name:  "watch, w",
+		Usage: "Re-render the table on an interval instead of exiting after the first render",
+	}
+}
+
+func watchIntervalFlag() cli.DurationFlag {
+	return cli.DurationFlag{
+		Name:  watchIntervalFlagName,
+		Usage: "How often to re-render the table in --watch mode",
+		Value: defaultWatchInterval,
+	}
+}
+
+// isTerminal reports whether stdout is a TTY; watchLoop only overwrites
+// prior output when it is.
+func isTerminal() bool {
+	fi, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return (fi.Mode() & os.ModeCharDevice) != 0
+}
+
+// clearScreen moves the cursor back up over the last render's lines.
+func clearScreen(lines int) {
+	if lines <= 0 {
+		return
+	}
+	fmt.Printf("\033[%dA\033[J", lines)
+}
+
+// hostCache holds the most recently seen HostResponse for each host ID.
+type hostCache struct {
+	order []uint
+	byID  map[uint]kolide.HostResponse
+}
+
+func newHostCache() *hostCache {
+	return &hostCache{byID: make(map[uint]kolide.HostResponse)}
+}
+
+func (hc *hostCache) merge(hosts []kolide.HostResponse) {
+	for _, host := range hosts {
+		if _, ok := hc.byID[host.Host.ID]; !ok {
+			hc.order = append(hc.order, host.Host.ID)
+		}
+		hc.byID[host.Host.ID] = host
+	}
+}
+
+func (hc *hostCache) list() []kolide.HostResponse {
+	hosts := make([]kolide.HostResponse, 0, len(hc.order))
+	for _, id := range hc.order {
+		hosts = append(hosts, hc.byID[id])
+	}
+	return hosts
+}
+
+// reset replaces the cache wholesale with hosts, so a host deleted from
+// Fleet stops being rendered instead of lingering forever.
+func (hc *hostCache) reset(hosts []kolide.HostResponse) {
+	hc.order = hc.order[:0]
+	hc.byID = make(map[uint]kolide.HostResponse, len(hosts))
+	hc.merge(hosts)
+}
+
+// watchLoop calls render every interval. render returns the number of lines
+// it printed so the next call knows how far to move the cursor back up.
+func watchLoop(interval time.Duration, render func() (int, error)) error {
+	tty := isTerminal()
+
+	for {
+		lines, err := render()
+		if err != nil {
+			return err
+		}
+
+		time.Sleep(interval)
+
+		if tty {
+			clearScreen(lines)
+		}
+	}
+}
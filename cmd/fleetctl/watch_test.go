@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/kolide/fleet/server/kolide"
+)
+
+func TestHostCacheMerge(t *testing.T) {
+	cache := newHostCache()
+
+	cache.merge([]kolide.HostResponse{
+		{Host: kolide.Host{ID: 1}, DisplayText: "one"},
+		{Host: kolide.Host{ID: 2}, DisplayText: "two"},
+	})
+	cache.merge([]kolide.HostResponse{
+		{Host: kolide.Host{ID: 1}, DisplayText: "one-updated"},
+	})
+
+	hosts := cache.list()
+	if len(hosts) != 2 {
+		t.Fatalf("expected 2 hosts, got %d", len(hosts))
+	}
+	if hosts[0].DisplayText != "one-updated" {
+		t.Errorf("expected host 1 to be updated in place, got %q", hosts[0].DisplayText)
+	}
+	if hosts[1].DisplayText != "two" {
+		t.Errorf("expected host 2 untouched, got %q", hosts[1].DisplayText)
+	}
+}
+
+func TestHostCacheReset(t *testing.T) {
+	cache := newHostCache()
+	cache.merge([]kolide.HostResponse{
+		{Host: kolide.Host{ID: 1}},
+		{Host: kolide.Host{ID: 2}},
+	})
+
+	cache.reset([]kolide.HostResponse{
+		{Host: kolide.Host{ID: 2}},
+	})
+
+	hosts := cache.list()
+	if len(hosts) != 1 || hosts[0].Host.ID != 2 {
+		t.Fatalf("expected reset to drop host 1, got %+v", hosts)
+	}
+}
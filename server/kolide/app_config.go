@@ -0,0 +1,13 @@
+package kolide
+
+// AppConfig is the spec.Spec payload returned by GET config.
+type AppConfig struct {
+	OrgName    string `json:"org_name"`
+	OrgLogoURL string `json:"org_logo_url"`
+	ServerURL  string `json:"server_url"`
+}
+
+// Options is the osquery configuration returned by GET options. Option
+// values vary by deployment, so this is left as a generic map rather than a
+// fixed struct.
+type Options map[string]interface{}
@@ -0,0 +1,14 @@
+package kolide
+
+import "time"
+
+// EnrollSecretSpec is the spec.Spec payload returned by GET enroll_secret.
+type EnrollSecretSpec struct {
+	Secrets []EnrollSecret `json:"secrets"`
+}
+
+// EnrollSecret is a single osquery enroll secret.
+type EnrollSecret struct {
+	Secret    string    `json:"secret"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,58 @@
+package kolide
+
+import "time"
+
+// Host is a single enrolled osquery host.
+type Host struct {
+	ID             uint      `json:"id"`
+	UUID           string    `json:"uuid"`
+	HostName       string    `json:"hostname"`
+	Platform       string    `json:"platform"`
+	OSVersion      string    `json:"os_version"`
+	OsqueryVersion string    `json:"osquery_version"`
+	PrimaryIP      string    `json:"primary_ip"`
+	HardwareSerial string    `json:"hardware_serial"`
+	SeenTime       time.Time `json:"seen_time"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// HostResponse is what the Fleet API returns for a host, adding derived
+// display fields on top of the stored Host record.
+type HostResponse struct {
+	Host        Host   `json:"host"`
+	DisplayText string `json:"display_text"`
+	Status      string `json:"status"`
+}
+
+// HostSpec returns the spec.Spec payload for this host, in a form that can
+// be round-tripped through `fleetctl apply`.
+func (h HostResponse) HostSpec() *HostSpec {
+	return &HostSpec{
+		Hostname:       h.Host.HostName,
+		Platform:       h.Host.Platform,
+		OSVersion:      h.Host.OSVersion,
+		OsqueryVersion: h.Host.OsqueryVersion,
+		PrimaryIP:      h.Host.PrimaryIP,
+		HardwareSerial: h.Host.HardwareSerial,
+	}
+}
+
+// HostSpec is the spec.Spec payload for a host.
+type HostSpec struct {
+	Hostname       string `json:"hostname"`
+	Platform       string `json:"platform"`
+	OSVersion      string `json:"os_version"`
+	OsqueryVersion string `json:"osquery_version"`
+	PrimaryIP      string `json:"primary_ip"`
+	HardwareSerial string `json:"hardware_serial"`
+}
+
+// HostListOptions is the server-side filter for listing hosts, pushed down
+// to the API so fleetctl never has to pull every host just to filter
+// client-side.
+type HostListOptions struct {
+	Platform         string   `json:"platform,omitempty"`
+	Status           string   `json:"status,omitempty"`
+	Labels           []string `json:"labels,omitempty"`
+	HostnameContains string   `json:"hostname_contains,omitempty"`
+}
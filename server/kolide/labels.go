@@ -0,0 +1,9 @@
+package kolide
+
+// LabelSpec is the spec.Spec payload for a label.
+type LabelSpec struct {
+	Name        string `json:"name"`
+	Platform    string `json:"platform"`
+	Description string `json:"description"`
+	Query       string `json:"query"`
+}
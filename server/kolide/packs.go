@@ -0,0 +1,25 @@
+package kolide
+
+// PackSpec is the spec.Spec payload for a pack.
+type PackSpec struct {
+	Name        string          `json:"name"`
+	Platform    string          `json:"platform"`
+	Description string          `json:"description"`
+	Targets     PackSpecTargets `json:"targets"`
+	Queries     []PackSpecQuery `json:"queries"`
+}
+
+// PackSpecTargets lists the labels and hosts a pack runs on.
+type PackSpecTargets struct {
+	Labels []string `json:"labels"`
+	Hosts  []string `json:"hosts"`
+}
+
+// PackSpecQuery is one scheduled query entry within a pack.
+type PackSpecQuery struct {
+	QueryName string `json:"query"`
+	Name      string `json:"name"`
+	Interval  uint   `json:"interval"`
+	Removed   bool   `json:"removed"`
+	Snapshot  bool   `json:"snapshot"`
+}
@@ -0,0 +1,9 @@
+package kolide
+
+// QuerySpec is the spec.Spec payload for a query, as read from or written
+// to a fleetctl apply YAML/JSON document.
+type QuerySpec struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Query       string `json:"query"`
+}
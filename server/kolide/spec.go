@@ -0,0 +1,5 @@
+package kolide
+
+// ApiVersion is the apiVersion field fleetctl writes into and expects on
+// every spec document (queries, packs, labels, config, ...).
+const ApiVersion = "v1"
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/kolide/fleet/server/kolide"
+)
+
+func (c *Client) GetAppConfig() (*kolide.AppConfig, error) {
+	var config kolide.AppConfig
+	if err := c.do(http.MethodGet, "/api/v1/fleet/config", nil, nil, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (c *Client) GetOptions() (kolide.Options, error) {
+	var options kolide.Options
+	if err := c.do(http.MethodGet, "/api/v1/fleet/options", nil, nil, &options); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+func (c *Client) GetEnrollSecretSpec() (*kolide.EnrollSecretSpec, error) {
+	var spec kolide.EnrollSecretSpec
+	if err := c.do(http.MethodGet, "/api/v1/fleet/spec/enroll_secret", nil, nil, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
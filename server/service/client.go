@@ -0,0 +1,107 @@
+// Package service implements the Fleet API client used by fleetctl.
+package service
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// Client talks to the Fleet server's HTTP API.
+type Client struct {
+	baseURL *url.URL
+	http    *http.Client
+	token   string
+}
+
+// NewClient creates a Fleet API client for addr. If insecureSkipVerify is
+// true, or rootCA is set, the returned client trusts accordingly; tlsCert
+// (a client certificate) is included when set.
+func NewClient(addr string, insecureSkipVerify bool, rootCA, tlsCert string) (*Client, error) {
+	baseURL, err := url.Parse(addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing fleet address %q", addr)
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+
+	if rootCA != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(rootCA)) {
+			return nil, errors.New("invalid root CA")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if tlsCert != "" {
+		cert, err := tls.X509KeyPair([]byte(tlsCert), []byte(tlsCert))
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &Client{
+		baseURL: baseURL,
+		http:    &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}},
+	}, nil
+}
+
+// SetToken sets the bearer token used to authenticate subsequent requests.
+func (c *Client) SetToken(token string) {
+	c.token = token
+}
+
+func (c *Client) do(method, path string, query url.Values, body, out interface{}) error {
+	u := *c.baseURL
+	u.Path = path
+	if query != nil {
+		u.RawQuery = query.Encode()
+	}
+
+	var reqBody *bytes.Buffer
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "marshaling request body")
+		}
+		reqBody = bytes.NewBuffer(b)
+	} else {
+		reqBody = bytes.NewBuffer(nil)
+	}
+
+	req, err := http.NewRequest(method, u.String(), reqBody)
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "requesting %s", path)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("%s %s: unexpected status %s", method, path, resp.Status)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.Wrapf(err, "decoding response from %s", path)
+	}
+
+	return nil
+}
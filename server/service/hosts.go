@@ -0,0 +1,55 @@
+package service
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kolide/fleet/server/kolide"
+)
+
+func hostListQuery(opts kolide.HostListOptions) url.Values {
+	q := url.Values{}
+	if opts.Platform != "" {
+		q.Set("platform", opts.Platform)
+	}
+	if opts.Status != "" {
+		q.Set("status", opts.Status)
+	}
+	if opts.HostnameContains != "" {
+		q.Set("hostname_contains", opts.HostnameContains)
+	}
+	if len(opts.Labels) > 0 {
+		q.Set("labels", strings.Join(opts.Labels, ","))
+	}
+	return q
+}
+
+// GetHosts lists hosts matching opts, with all filtering applied
+// server-side so the client never has to pull every host.
+func (c *Client) GetHosts(opts kolide.HostListOptions) ([]kolide.HostResponse, error) {
+	var body struct {
+		Hosts []kolide.HostResponse `json:"hosts"`
+	}
+	if err := c.do(http.MethodGet, "/api/v1/fleet/hosts", hostListQuery(opts), nil, &body); err != nil {
+		return nil, err
+	}
+	return body.Hosts, nil
+}
+
+// GetHostsSince lists only hosts updated after since, matching opts, so
+// --watch can poll incrementally instead of refetching every host on each
+// tick.
+func (c *Client) GetHostsSince(opts kolide.HostListOptions, since time.Time) ([]kolide.HostResponse, error) {
+	query := hostListQuery(opts)
+	query.Set("since", since.Format(time.RFC3339Nano))
+
+	var body struct {
+		Hosts []kolide.HostResponse `json:"hosts"`
+	}
+	if err := c.do(http.MethodGet, "/api/v1/fleet/hosts", query, nil, &body); err != nil {
+		return nil, err
+	}
+	return body.Hosts, nil
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/kolide/fleet/server/kolide"
+)
+
+func (c *Client) GetLabels() ([]*kolide.LabelSpec, error) {
+	var body struct {
+		Specs []*kolide.LabelSpec `json:"specs"`
+	}
+	if err := c.do(http.MethodGet, "/api/v1/fleet/spec/labels", nil, nil, &body); err != nil {
+		return nil, err
+	}
+	return body.Specs, nil
+}
+
+func (c *Client) GetLabel(name string) (*kolide.LabelSpec, error) {
+	var body struct {
+		Spec *kolide.LabelSpec `json:"spec"`
+	}
+	if err := c.do(http.MethodGet, "/api/v1/fleet/spec/labels/"+name, nil, nil, &body); err != nil {
+		return nil, err
+	}
+	return body.Spec, nil
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/kolide/fleet/server/kolide"
+)
+
+func (c *Client) GetPacks() ([]*kolide.PackSpec, error) {
+	var body struct {
+		Specs []*kolide.PackSpec `json:"specs"`
+	}
+	if err := c.do(http.MethodGet, "/api/v1/fleet/spec/packs", nil, nil, &body); err != nil {
+		return nil, err
+	}
+	return body.Specs, nil
+}
+
+func (c *Client) GetPack(name string) (*kolide.PackSpec, error) {
+	var body struct {
+		Spec *kolide.PackSpec `json:"spec"`
+	}
+	if err := c.do(http.MethodGet, "/api/v1/fleet/spec/packs/"+name, nil, nil, &body); err != nil {
+		return nil, err
+	}
+	return body.Spec, nil
+}
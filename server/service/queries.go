@@ -0,0 +1,27 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/kolide/fleet/server/kolide"
+)
+
+func (c *Client) GetQueries() ([]*kolide.QuerySpec, error) {
+	var body struct {
+		Specs []*kolide.QuerySpec `json:"specs"`
+	}
+	if err := c.do(http.MethodGet, "/api/v1/fleet/spec/queries", nil, nil, &body); err != nil {
+		return nil, err
+	}
+	return body.Specs, nil
+}
+
+func (c *Client) GetQuery(name string) (*kolide.QuerySpec, error) {
+	var body struct {
+		Spec *kolide.QuerySpec `json:"spec"`
+	}
+	if err := c.do(http.MethodGet, "/api/v1/fleet/spec/queries/"+name, nil, nil, &body); err != nil {
+		return nil, err
+	}
+	return body.Spec, nil
+}